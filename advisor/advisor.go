@@ -0,0 +1,415 @@
+// Package advisor analyzes a representative SQL workload against fire_calls
+// and recommends index changes based on planner cardinality error, mirroring
+// the approach used by TiDB's cardinality estimation fixes: rather than
+// trusting the planner's row estimates, we run EXPLAIN ANALYZE and compare
+// what the planner predicted against what actually happened.
+package advisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"database-optimizer/db"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Report summarizes the findings from analyzing a workload.
+type Report struct {
+	StatementsAnalyzed int
+	Suggestions        []IndexSuggestion
+}
+
+// SuggestionKind distinguishes a new index recommendation from a drop
+// recommendation for an index that the planner never chose.
+type SuggestionKind string
+
+const (
+	SuggestCreate SuggestionKind = "create"
+	SuggestDrop   SuggestionKind = "drop"
+)
+
+// IndexSuggestion is a single actionable recommendation produced by Analyze.
+type IndexSuggestion struct {
+	Kind      SuggestionKind
+	Table     string
+	Column    string
+	IndexName string
+	Score     float64
+	Reason    string
+}
+
+// planNode mirrors the subset of PostgreSQL's EXPLAIN (FORMAT JSON) plan node
+// shape that we care about. Field names match Postgres's JSON keys exactly.
+type planNode struct {
+	NodeType            string     `json:"Node Type"`
+	RelationName        string     `json:"Relation Name,omitempty"`
+	IndexName           string     `json:"Index Name,omitempty"`
+	Filter              string     `json:"Filter,omitempty"`
+	IndexCond           string     `json:"Index Cond,omitempty"`
+	PlanRows            int64      `json:"Plan Rows"`
+	ActualRows          int64      `json:"Actual Rows"`
+	RowsRemovedByFilter int64      `json:"Rows Removed by Filter,omitempty"`
+	Plans               []planNode `json:"Plans,omitempty"`
+}
+
+type explainResult struct {
+	Plan planNode `json:"Plan"`
+}
+
+// filterStat accumulates the cost-weighted evidence that a column deserves an
+// index: how often it shows up in a costly filter, and how wrong the planner
+// was about it.
+type filterStat struct {
+	column       string
+	occurrences  int
+	weightedCost float64
+}
+
+var filterColumnRe = regexp.MustCompile(`^\s*\(*\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(?:=|<|>|<=|>=|~~|<>)`)
+
+// Analyze runs EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) for every statement in
+// workload, walks the resulting plan trees, and proposes index changes for
+// fire_calls based on which filter predicates are costing the most actual
+// rows relative to what the planner expected.
+func Analyze(workload []string) (*Report, error) {
+	pool := db.GetPool()
+	ctx := context.Background()
+
+	filters := make(map[string]*filterStat)
+	indexUsage := make(map[string]int)
+	analyzed := 0
+
+	for _, stmt := range workload {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		analyzed++
+
+		var raw []byte
+		row := pool.QueryRow(ctx, fmt.Sprintf("EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) %s", stmt))
+		if err := row.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("failed to explain statement %q: %w", stmt, err)
+		}
+
+		var results []explainResult
+		if err := json.Unmarshal(raw, &results); err != nil {
+			return nil, fmt.Errorf("failed to parse explain output for %q: %w", stmt, err)
+		}
+		if len(results) == 0 {
+			continue
+		}
+
+		walkPlan(&results[0].Plan, filters, indexUsage)
+	}
+
+	existingIndexes, err := loadIndexes(ctx, pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load existing indexes: %w", err)
+	}
+
+	leadingColumns := make(map[string]bool, len(existingIndexes))
+	for _, idx := range existingIndexes {
+		leadingColumns[idx.leadingColumn] = true
+	}
+
+	suggestions := buildCreateSuggestions(filters, leadingColumns)
+	suggestions = append(suggestions, buildDropSuggestions(existingIndexes, indexUsage)...)
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		if suggestions[i].Column != suggestions[j].Column {
+			return suggestions[i].Column < suggestions[j].Column
+		}
+		return suggestions[i].IndexName < suggestions[j].IndexName
+	})
+
+	return &Report{
+		StatementsAnalyzed: analyzed,
+		Suggestions:        suggestions,
+	}, nil
+}
+
+// walkPlan recurses through a plan tree, updating filter cost statistics and
+// index usage counts as it goes. Row counts are clamped to at least 1 so a
+// "Rows Removed by Filter: 0" node can't produce a divide-by-zero or an
+// artificially huge q_error.
+func walkPlan(node *planNode, filters map[string]*filterStat, indexUsage map[string]int) {
+	if node == nil {
+		return
+	}
+
+	switch node.NodeType {
+	case "Index Scan", "Index Only Scan", "Bitmap Index Scan":
+		if node.IndexName != "" {
+			indexUsage[node.IndexName]++
+		}
+	}
+
+	if node.Filter != "" {
+		estimated := clampMin1(node.PlanRows)
+		actual := clampMin1(node.ActualRows + node.RowsRemovedByFilter)
+		qError := qError(estimated, actual)
+		weightedCost := float64(actual) * qError
+
+		for _, pred := range splitTopLevelPredicates(node.Filter) {
+			column := filterColumnRe.FindStringSubmatch(pred)
+			if column == nil {
+				continue
+			}
+			col := column[1]
+			stat, ok := filters[col]
+			if !ok {
+				stat = &filterStat{column: col}
+				filters[col] = stat
+			}
+			stat.occurrences++
+			stat.weightedCost += weightedCost
+		}
+	}
+
+	for i := range node.Plans {
+		walkPlan(&node.Plans[i], filters, indexUsage)
+	}
+}
+
+// qError is the planner cardinality error metric used by TiDB: the ratio
+// between actual and estimated row counts, always expressed as a number
+// greater than or equal to 1 regardless of which direction the planner
+// missed in.
+func qError(estimated, actual int64) float64 {
+	e, a := float64(estimated), float64(actual)
+	if a > e {
+		return a / e
+	}
+	return e / a
+}
+
+func clampMin1(n int64) int64 {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// splitTopLevelPredicates splits a Postgres Filter string into its individual
+// AND/OR-joined predicates so each one's column can be attributed the node's
+// cost separately. Postgres double-wraps compound filters in parens, e.g.
+// "((call_type = 'Medical Incident'::text) AND (unit_type = 'ENGINE'::text))",
+// so a naive single-predicate regex only ever sees the first column. AND/OR
+// are only treated as separators at paren depth 0, so they aren't mistaken
+// for keywords appearing inside a nested sub-expression.
+func splitTopLevelPredicates(filter string) []string {
+	filter = stripOuterParens(filter)
+
+	var predicates []string
+	depth := 0
+	start := 0
+
+	for i := 0; i < len(filter); i++ {
+		switch filter[i] {
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth--
+			continue
+		}
+		if depth != 0 {
+			continue
+		}
+		if kw, ok := matchKeywordAt(filter, i, "AND", "OR"); ok {
+			predicates = append(predicates, filter[start:i])
+			i += len(kw) - 1
+			start = i + 1
+		}
+	}
+	predicates = append(predicates, filter[start:])
+
+	return predicates
+}
+
+// stripOuterParens removes a redundant outer paren pair that wraps the
+// entire expression, e.g. "((a = 1) AND (b = 2))" -> "(a = 1) AND (b = 2)".
+// Postgres wraps compound Filter expressions this way, which would otherwise
+// put top-level AND/OR one paren depth too deep for splitTopLevelPredicates
+// to see them.
+func stripOuterParens(filter string) string {
+	for len(filter) >= 2 && filter[0] == '(' && filter[len(filter)-1] == ')' {
+		depth := 0
+		closesAtEnd := true
+		for i := 0; i < len(filter); i++ {
+			switch filter[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 && i != len(filter)-1 {
+					closesAtEnd = false
+				}
+			}
+		}
+		if !closesAtEnd {
+			return filter
+		}
+		filter = strings.TrimSpace(filter[1 : len(filter)-1])
+	}
+	return filter
+}
+
+// matchKeywordAt reports whether one of keywords matches filter at pos as a
+// whole word (case-insensitively), so "AND" doesn't match inside "BRAND".
+func matchKeywordAt(filter string, pos int, keywords ...string) (string, bool) {
+	if pos > 0 && isIdentByte(filter[pos-1]) {
+		return "", false
+	}
+	for _, kw := range keywords {
+		end := pos + len(kw)
+		if end > len(filter) {
+			continue
+		}
+		if !strings.EqualFold(filter[pos:end], kw) {
+			continue
+		}
+		if end < len(filter) && isIdentByte(filter[end]) {
+			continue
+		}
+		return kw, true
+	}
+	return "", false
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// createThreshold is the minimum weighted cost a filter column must
+// accumulate across the workload before we suggest indexing it.
+const createThreshold = 1000.0
+
+func buildCreateSuggestions(filters map[string]*filterStat, leadingColumns map[string]bool) []IndexSuggestion {
+	var suggestions []IndexSuggestion
+	for col, stat := range filters {
+		if leadingColumns[col] {
+			continue
+		}
+		if stat.weightedCost < createThreshold {
+			continue
+		}
+		suggestions = append(suggestions, IndexSuggestion{
+			Kind:   SuggestCreate,
+			Table:  "fire_calls",
+			Column: col,
+			Score:  stat.weightedCost,
+			Reason: fmt.Sprintf("column %q appeared in %d costly filter(s) with weighted cost %.0f and has no covering index", col, stat.occurrences, stat.weightedCost),
+		})
+	}
+	return suggestions
+}
+
+func buildDropSuggestions(indexes []indexInfo, usage map[string]int) []IndexSuggestion {
+	byLeadingColumn := make(map[string][]indexInfo)
+	for _, idx := range indexes {
+		byLeadingColumn[idx.leadingColumn] = append(byLeadingColumn[idx.leadingColumn], idx)
+	}
+
+	var suggestions []IndexSuggestion
+	for _, group := range byLeadingColumn {
+		if len(group) < 2 {
+			continue
+		}
+		anyUsed := false
+		for _, idx := range group {
+			if usage[idx.name] > 0 {
+				anyUsed = true
+				break
+			}
+		}
+		if !anyUsed {
+			continue
+		}
+		for _, idx := range group {
+			if usage[idx.name] == 0 {
+				suggestions = append(suggestions, IndexSuggestion{
+					Kind:      SuggestDrop,
+					Table:     "fire_calls",
+					Column:    idx.leadingColumn,
+					IndexName: idx.name,
+					Score:     float64(idx.sizeBytes),
+					Reason:    fmt.Sprintf("index %q shares leading column %q with another index the planner actually used, but was never chosen across the workload (reclaims %d bytes)", idx.name, idx.leadingColumn, idx.sizeBytes),
+				})
+			}
+		}
+	}
+	return suggestions
+}
+
+type indexInfo struct {
+	name          string
+	leadingColumn string
+	sizeBytes     int64
+}
+
+var leadingColumnRe = regexp.MustCompile(`\(([^,)]+)`)
+
+// loadIndexes reads the current indexes on fire_calls from pg_indexes,
+// extracts each one's leading column from its index definition, and reads
+// its on-disk size so drop suggestions can be ranked by how much space
+// they'd reclaim.
+func loadIndexes(ctx context.Context, pool *pgxpool.Pool) ([]indexInfo, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT indexname, indexdef, pg_relation_size(indexname::regclass)
+		FROM pg_indexes
+		WHERE tablename = 'fire_calls'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_indexes: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []indexInfo
+	for rows.Next() {
+		var name, def string
+		var sizeBytes int64
+		if err := rows.Scan(&name, &def, &sizeBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan index row: %w", err)
+		}
+
+		leading := ""
+		if match := leadingColumnRe.FindStringSubmatch(def); match != nil {
+			leading = strings.TrimSpace(strings.Split(match[1], " ")[0])
+		}
+
+		indexes = append(indexes, indexInfo{name: name, leadingColumn: leading, sizeBytes: sizeBytes})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating index rows: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// Print writes a human-readable summary of the report's suggestions.
+func (r *Report) Print() {
+	fmt.Printf("\n🔍 Index advisor analyzed %d statement(s)\n", r.StatementsAnalyzed)
+	if len(r.Suggestions) == 0 {
+		fmt.Println("✅ No index changes recommended")
+		return
+	}
+
+	for _, s := range r.Suggestions {
+		switch s.Kind {
+		case SuggestCreate:
+			fmt.Printf("➕ CREATE INDEX on %s(%s) — %s\n", s.Table, s.Column, s.Reason)
+		case SuggestDrop:
+			fmt.Printf("➖ DROP INDEX %s on %s(%s) — %s\n", s.IndexName, s.Table, s.Column, s.Reason)
+		}
+	}
+}
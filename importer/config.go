@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"fmt"
+	"time"
+)
+
+// ImportStrategy names a bulk-loading code path. Which path runs is decided
+// by the import method called; Config.Strategy only affects
+// ImportFromFileCopy.
+type ImportStrategy int
+
+const (
+	// StrategyInsert batches rows into multi-row INSERT statements, as done
+	// by ImportFromFile and ImportFromFileGoRoutine.
+	StrategyInsert ImportStrategy = iota
+	// StrategyCopy streams rows into fire_calls using the PostgreSQL COPY
+	// protocol via ImportFromFileCopy.
+	StrategyCopy
+	// StrategyCopyUnlogged is StrategyCopy with fire_calls temporarily set
+	// UNLOGGED for the duration of the import, trading WAL durability for
+	// throughput, then restored to LOGGED once the import completes.
+	StrategyCopyUnlogged
+)
+
+// String returns the strategy's name.
+func (s ImportStrategy) String() string {
+	switch s {
+	case StrategyInsert:
+		return "insert"
+	case StrategyCopy:
+		return "copy"
+	case StrategyCopyUnlogged:
+		return "copy_unlogged"
+	default:
+		return "unknown"
+	}
+}
+
+// ConflictAction controls what ImportFromFileCopy does when an incoming row's
+// primary key collides with a row already in fire_calls.
+type ConflictAction int
+
+const (
+	// OnConflictError lets the primary key violation surface as an error,
+	// matching the behavior of the INSERT-based import methods.
+	OnConflictError ConflictAction = iota
+	// OnConflictDoNothing discards incoming rows that collide with existing ones.
+	OnConflictDoNothing
+	// OnConflictUpdate overwrites the existing row with the incoming one.
+	OnConflictUpdate
+)
+
+// Config holds the tunables for a bulk CSV import. A zero Config is not
+// ready to use; start from DefaultConfig and override what's needed.
+type Config struct {
+	// Strategy is read only by ImportFromFileCopy, which streams rows with
+	// COPY for every strategy; StrategyCopyUnlogged additionally marks
+	// fire_calls UNLOGGED for the import. ImportFromFile and
+	// ImportFromFileGoRoutine always use INSERT and ignore it.
+	Strategy ImportStrategy
+
+	// CSVSeparator is the field delimiter passed to encoding/csv.
+	CSVSeparator rune
+	// CSVDelimiter is the quote character surrounding quoted fields.
+	// encoding/csv only supports the standard '"'; NewCSVImporterWithConfig
+	// rejects any other non-zero value rather than silently ignoring it.
+	CSVDelimiter rune
+	// NullString is the field value that should be parsed as SQL NULL
+	// instead of as a zero-length string. Defaults to "".
+	NullString string
+
+	// ReadTimeout bounds how long a single COPY batch is allowed to take.
+	ReadTimeout time.Duration
+	// MaxBatchBytes caps the estimated size of a batch, so a batch is
+	// flushed early if it would otherwise exceed this many bytes even
+	// though it hasn't reached batchSize rows yet.
+	MaxBatchBytes int64
+
+	// SkipMalformed, when true, drops rows that fail to parse instead of
+	// aborting the import. Errors from the CSV reader itself, such as a bare
+	// quote, still abort.
+	SkipMalformed bool
+
+	// OnConflict controls primary key collision handling for
+	// ImportFromFileCopy.
+	OnConflict ConflictAction
+}
+
+// Validate reports an error if cfg holds a combination encoding/csv or the
+// importer can't actually honor, such as a CSVDelimiter other than '"'.
+func (cfg Config) Validate() error {
+	if cfg.CSVDelimiter != 0 && cfg.CSVDelimiter != '"' {
+		return fmt.Errorf("CSVDelimiter %q is unsupported: encoding/csv only quotes fields with '\"'", cfg.CSVDelimiter)
+	}
+	return nil
+}
+
+// DefaultConfig returns the tunables that match CSVImporter's original,
+// hardcoded behavior.
+func DefaultConfig() Config {
+	return Config{
+		Strategy:      StrategyInsert,
+		CSVSeparator:  ',',
+		CSVDelimiter:  '"',
+		NullString:    "",
+		ReadTimeout:   30 * time.Second,
+		MaxBatchBytes: 16 * 1024 * 1024,
+		SkipMalformed: true,
+		OnConflict:    OnConflictError,
+	}
+}
@@ -0,0 +1,293 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// fireCallsColumns is the column order used by both the INSERT and COPY
+// import paths; it must match parseRow's output order.
+var fireCallsColumns = []string{
+	"call_number", "unit_id", "incident_number", "call_type", "call_date", "watch_date",
+	"call_final_disposition", "available_dt_tm", "address", "city", "zipcode", "battalion",
+	"station_area", "box", "original_priority", "priority", "final_priority", "als_unit",
+	"call_type_group", "num_alarms", "unit_type", "unit_sequence_in_call_dispatch",
+	"fire_prevention_district", "supervisor_district", "neighborhood", "location", "row_id", "delay",
+}
+
+// copyStagingTable is the name of the staging table ImportFromFileCopy
+// creates when Config.OnConflict != OnConflictError. Imports using a
+// conflict strategy are expected to run one at a time.
+const copyStagingTable = "fire_calls_copy_staging"
+
+// ImportFromFileCopy imports data from a CSV file using the PostgreSQL COPY
+// protocol instead of batched INSERT statements, avoiding the 65535
+// parameter limit that caps ImportFromFile's throughput on large batches.
+// numWorkers controls how many connections stream COPY batches concurrently.
+func (imp *CSVImporter) ImportFromFileCopy(filepath string, numWorkers int) (int, error) {
+	ctx := context.Background()
+
+	if imp.Config.Strategy == StrategyCopyUnlogged {
+		if err := imp.setTableLogged(ctx, false); err != nil {
+			return 0, fmt.Errorf("failed to mark fire_calls unlogged: %w", err)
+		}
+		defer func() {
+			if err := imp.setTableLogged(ctx, true); err != nil {
+				fmt.Printf("⚠️  Warning: failed to restore fire_calls to logged: %v\n", err)
+			}
+		}()
+	}
+
+	targetTable := "fire_calls"
+	if imp.Config.OnConflict != OnConflictError {
+		if err := imp.createStagingTable(ctx); err != nil {
+			return 0, err
+		}
+		defer imp.dropStagingTable(ctx)
+		targetTable = copyStagingTable
+	}
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = imp.Config.CSVSeparator
+	reader.FieldsPerRecord = -1 // field count is checked by parseRow
+
+	if _, err := reader.Read(); err != nil {
+		return 0, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	recordChan := make(chan []string, imp.batchSize*2)
+	batchChan := make(chan [][]interface{}, numWorkers)
+	errorChan := make(chan error, numWorkers+1)
+
+	go func() {
+		defer close(recordChan)
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errorChan <- fmt.Errorf("error reading CSV: %w", err)
+				return
+			}
+			recordChan <- record
+		}
+	}()
+
+	var parseWg sync.WaitGroup
+	parseWg.Add(1)
+	go func() {
+		defer parseWg.Done()
+		defer close(batchChan)
+
+		batch := make([][]interface{}, 0, imp.batchSize)
+		batchBytes := int64(0)
+
+		flush := func() {
+			if len(batch) > 0 {
+				batchChan <- batch
+				batch = make([][]interface{}, 0, imp.batchSize)
+				batchBytes = 0
+			}
+		}
+
+		for record := range recordChan {
+			row, err := imp.parseRow(record)
+			if err != nil {
+				if !imp.Config.SkipMalformed {
+					errorChan <- fmt.Errorf("malformed row: %w", err)
+					return
+				}
+				continue
+			}
+
+			size := recordByteSize(record)
+			if imp.Config.MaxBatchBytes > 0 && len(batch) > 0 && batchBytes+size > imp.Config.MaxBatchBytes {
+				flush()
+			}
+
+			batch = append(batch, row)
+			batchBytes += size
+
+			if len(batch) >= imp.batchSize {
+				flush()
+			}
+		}
+		flush()
+	}()
+
+	var totalRecords int
+	var recordMutex sync.Mutex
+	var copyWg sync.WaitGroup
+
+	for i := 0; i < numWorkers; i++ {
+		copyWg.Add(1)
+		go func(workerID int) {
+			defer copyWg.Done()
+			for batch := range batchChan {
+				n, err := imp.copyBatch(ctx, targetTable, batch)
+				if err != nil {
+					errorChan <- fmt.Errorf("copy worker %d: %w", workerID, err)
+					return
+				}
+				recordMutex.Lock()
+				totalRecords += n
+				recordMutex.Unlock()
+			}
+		}(i)
+	}
+
+	doneChan := make(chan struct{})
+	go func() {
+		parseWg.Wait()
+		copyWg.Wait()
+		close(doneChan)
+	}()
+
+	select {
+	case err := <-errorChan:
+		return totalRecords, err
+	case <-doneChan:
+	}
+
+	if targetTable == copyStagingTable {
+		merged, err := imp.mergeStaging(ctx)
+		if err != nil {
+			return totalRecords, err
+		}
+		return merged, nil
+	}
+
+	return totalRecords, nil
+}
+
+// copyBatch streams a single parsed batch into table via COPY FROM, applying
+// Config.ReadTimeout as a per-batch deadline.
+func (imp *CSVImporter) copyBatch(ctx context.Context, table string, batch [][]interface{}) (int, error) {
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	if imp.Config.ReadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, imp.Config.ReadTimeout)
+		defer cancel()
+	}
+
+	n, err := imp.pool.CopyFrom(ctx, pgx.Identifier{table}, fireCallsColumns, pgx.CopyFromRows(batch))
+	if err != nil {
+		return 0, fmt.Errorf("copy batch failed: %w", err)
+	}
+
+	return int(n), nil
+}
+
+// createStagingTable (re)creates a staging table shaped like fire_calls for
+// ImportFromFileCopy to COPY into before merging with an ON CONFLICT clause.
+// It deliberately omits INCLUDING INDEXES: carrying over fire_calls' row_id
+// primary key would make COPY itself reject a source CSV containing a
+// repeated row_id, rather than letting mergeStaging dedupe it.
+func (imp *CSVImporter) createStagingTable(ctx context.Context) error {
+	imp.dropStagingTable(ctx)
+
+	_, err := imp.pool.Exec(ctx, fmt.Sprintf(
+		"CREATE UNLOGGED TABLE %s (LIKE fire_calls INCLUDING DEFAULTS)", copyStagingTable,
+	))
+	if err != nil {
+		return fmt.Errorf("failed to create staging table: %w", err)
+	}
+	return nil
+}
+
+func (imp *CSVImporter) dropStagingTable(ctx context.Context) {
+	imp.pool.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s", copyStagingTable))
+}
+
+// mergeStaging moves rows from the staging table into fire_calls with the
+// configured conflict resolution, then reports how many rows landed.
+//
+// The staging table carries no unique constraint on row_id (COPY needs to
+// accept whatever the source CSV contains), so the merge SELECT dedupes with
+// DISTINCT ON (row_id) itself; otherwise a CSV with a repeated row_id would
+// make ON CONFLICT DO UPDATE try to affect the same fire_calls row twice in
+// one statement, which Postgres rejects outright.
+func (imp *CSVImporter) mergeStaging(ctx context.Context) (int, error) {
+	columnList := joinColumns(fireCallsColumns)
+
+	query := fmt.Sprintf(
+		"INSERT INTO fire_calls (%s) SELECT DISTINCT ON (row_id) %s FROM %s ORDER BY row_id",
+		columnList, columnList, copyStagingTable,
+	)
+
+	switch imp.Config.OnConflict {
+	case OnConflictDoNothing:
+		query += " ON CONFLICT (row_id) DO NOTHING"
+	case OnConflictUpdate:
+		query += " ON CONFLICT (row_id) DO UPDATE SET " + conflictUpdateAssignments(fireCallsColumns)
+	}
+
+	tag, err := imp.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge staging table into fire_calls: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// setTableLogged toggles fire_calls between LOGGED and UNLOGGED, used by
+// StrategyCopyUnlogged to skip WAL writes for the duration of an import.
+func (imp *CSVImporter) setTableLogged(ctx context.Context, logged bool) error {
+	mode := "UNLOGGED"
+	if logged {
+		mode = "LOGGED"
+	}
+	_, err := imp.pool.Exec(ctx, fmt.Sprintf("ALTER TABLE fire_calls SET %s", mode))
+	return err
+}
+
+func joinColumns(columns []string) string {
+	out := ""
+	for i, c := range columns {
+		if i > 0 {
+			out += ", "
+		}
+		out += c
+	}
+	return out
+}
+
+func conflictUpdateAssignments(columns []string) string {
+	out := ""
+	for _, c := range columns {
+		if c == "row_id" {
+			continue
+		}
+		if out != "" {
+			out += ", "
+		}
+		out += fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+	}
+	return out
+}
+
+// recordByteSize estimates the on-the-wire size of a raw CSV record, used to
+// enforce Config.MaxBatchBytes independently of row count.
+func recordByteSize(record []string) int64 {
+	var total int64
+	for _, field := range record {
+		total += int64(len(field))
+	}
+	return total
+}
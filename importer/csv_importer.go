@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 
+	"database-optimizer/profiler"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,14 +19,39 @@ import (
 type CSVImporter struct {
 	pool      *pgxpool.Pool
 	batchSize int
+	Config    Config
+
+	// BatchProfiler, if set, receives a child span for every batch that
+	// executeBatch inserts, annotated with batch size, byte count, and worker
+	// ID. Batches sent by ImportFromFileCopy are not recorded.
+	BatchProfiler *profiler.Operation
 }
 
-// NewCSVImporter creates a new CSV importer with the specified batch size
+// NewCSVImporter creates a new CSV importer with the specified batch size,
+// using DefaultConfig. Use NewCSVImporterWithConfig to customize delimiters,
+// null handling, conflict behavior, or import strategy.
 func NewCSVImporter(pool *pgxpool.Pool, batchSize int) *CSVImporter {
+	imp, err := NewCSVImporterWithConfig(pool, batchSize, DefaultConfig())
+	if err != nil {
+		// DefaultConfig is always valid; a failure here means the default
+		// itself regressed, which is a programmer error, not a runtime one.
+		panic(fmt.Sprintf("importer: DefaultConfig failed validation: %v", err))
+	}
+	return imp
+}
+
+// NewCSVImporterWithConfig creates a new CSV importer with the specified
+// batch size and tunables, rejecting a Config that Validate flags as
+// unsupported.
+func NewCSVImporterWithConfig(pool *pgxpool.Pool, batchSize int, cfg Config) (*CSVImporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid importer config: %w", err)
+	}
 	return &CSVImporter{
 		pool:      pool,
 		batchSize: batchSize,
-	}
+		Config:    cfg,
+	}, nil
 }
 
 // ImportFromFile imports data from a CSV file sequentially (single-threaded)
@@ -39,6 +66,8 @@ func (imp *CSVImporter) ImportFromFile(filepath string) (int, error) {
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	reader.Comma = imp.Config.CSVSeparator
+	reader.FieldsPerRecord = -1 // field count is checked by parseRow
 
 	// Read and skip header
 	_, err = reader.Read()
@@ -61,7 +90,9 @@ func (imp *CSVImporter) ImportFromFile(filepath string) (int, error) {
 		// Parse the record
 		row, err := imp.parseRow(record)
 		if err != nil {
-			// Skip invalid rows but log them
+			if !imp.Config.SkipMalformed {
+				return totalRecords, fmt.Errorf("malformed row: %w", err)
+			}
 			fmt.Printf("⚠️  Skipping invalid row: %v\n", err)
 			continue
 		}
@@ -70,7 +101,7 @@ func (imp *CSVImporter) ImportFromFile(filepath string) (int, error) {
 
 		// Insert batch when it reaches the batch size
 		if len(batch) >= imp.batchSize {
-			if err := imp.executeBatch(ctx, batch); err != nil {
+			if err := imp.executeBatch(ctx, batch, 0); err != nil {
 				return totalRecords, fmt.Errorf("failed to insert batch: %w", err)
 			}
 			totalRecords += len(batch)
@@ -80,7 +111,7 @@ func (imp *CSVImporter) ImportFromFile(filepath string) (int, error) {
 
 	// Insert remaining records
 	if len(batch) > 0 {
-		if err := imp.executeBatch(ctx, batch); err != nil {
+		if err := imp.executeBatch(ctx, batch, 0); err != nil {
 			return totalRecords, fmt.Errorf("failed to insert final batch: %w", err)
 		}
 		totalRecords += len(batch)
@@ -101,6 +132,8 @@ func (imp *CSVImporter) ImportFromFileGoRoutine(filepath string, numWorkers int)
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	reader.Comma = imp.Config.CSVSeparator
+	reader.FieldsPerRecord = -1 // field count is checked by parseRow
 
 	// Read and skip header
 	_, err = reader.Read()
@@ -144,7 +177,10 @@ func (imp *CSVImporter) ImportFromFileGoRoutine(filepath string, numWorkers int)
 			for record := range recordChan {
 				row, err := imp.parseRow(record)
 				if err != nil {
-					// Skip invalid rows
+					if !imp.Config.SkipMalformed {
+						errorChan <- fmt.Errorf("malformed row: %w", err)
+						return
+					}
 					continue
 				}
 
@@ -180,7 +216,7 @@ func (imp *CSVImporter) ImportFromFileGoRoutine(filepath string, numWorkers int)
 			defer insertWg.Done()
 
 			for batch := range batchChan {
-				if err := imp.executeBatch(ctx, batch); err != nil {
+				if err := imp.executeBatch(ctx, batch, workerID); err != nil {
 					errorChan <- fmt.Errorf("worker %d: %w", workerID, err)
 					return
 				}
@@ -215,7 +251,7 @@ func (imp *CSVImporter) parseRow(record []string) ([]interface{}, error) {
 
 	// Helper function to parse nullable integers
 	parseInt := func(s string) interface{} {
-		if s == "" {
+		if s == imp.Config.NullString {
 			return nil
 		}
 		val, err := strconv.Atoi(s)
@@ -227,7 +263,7 @@ func (imp *CSVImporter) parseRow(record []string) ([]interface{}, error) {
 
 	// Helper function to parse nullable floats
 	parseFloat := func(s string) interface{} {
-		if s == "" {
+		if s == imp.Config.NullString {
 			return nil
 		}
 		val, err := strconv.ParseFloat(s, 64)
@@ -245,7 +281,7 @@ func (imp *CSVImporter) parseRow(record []string) ([]interface{}, error) {
 
 	// Helper function to handle nullable strings
 	parseString := func(s string) interface{} {
-		if s == "" {
+		if s == imp.Config.NullString {
 			return nil
 		}
 		return s
@@ -285,12 +321,23 @@ func (imp *CSVImporter) parseRow(record []string) ([]interface{}, error) {
 	return row, nil
 }
 
-// executeBatch inserts a batch of rows into the database
-func (imp *CSVImporter) executeBatch(ctx context.Context, batch [][]interface{}) error {
+// executeBatch inserts a batch of rows into the database. workerID
+// identifies the insert worker that sends the batch (0 for the sequential
+// importer) and is recorded as a profiling attribute alongside batch size
+// and byte count when imp.BatchProfiler is set.
+func (imp *CSVImporter) executeBatch(ctx context.Context, batch [][]interface{}, workerID int) error {
 	if len(batch) == 0 {
 		return nil
 	}
 
+	if imp.BatchProfiler != nil {
+		op := imp.BatchProfiler.Start(fmt.Sprintf("batch_worker_%d", workerID))
+		op.SetAttribute("batch_size", len(batch))
+		op.SetAttribute("batch_bytes", batchByteSize(batch))
+		op.SetAttribute("worker_id", workerID)
+		defer op.End()
+	}
+
 	// Build the INSERT statement with placeholders
 	query := `INSERT INTO fire_calls (
 		call_number, unit_id, incident_number, call_type, call_date, watch_date,
@@ -327,3 +374,17 @@ func (imp *CSVImporter) executeBatch(ctx context.Context, batch [][]interface{})
 
 	return nil
 }
+
+// batchByteSize estimates a batch's size in bytes by summing the length of
+// its string-valued fields, for profiling attributes.
+func batchByteSize(batch [][]interface{}) int64 {
+	var total int64
+	for _, row := range batch {
+		for _, value := range row {
+			if s, ok := value.(string); ok {
+				total += int64(len(s))
+			}
+		}
+	}
+	return total
+}
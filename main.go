@@ -1,17 +1,34 @@
 package main
 
 import (
+	"context"
+	"database-optimizer/advisor"
 	"database-optimizer/db"
 	"database-optimizer/importer"
 	"database-optimizer/profiler"
 	"database-optimizer/schema"
+	"flag"
 	"fmt"
 	"log"
 
 	"github.com/joho/godotenv"
 )
 
+// sampleWorkload is a representative set of statements run against fire_calls
+// in production dashboards; the index advisor uses it to find filters that
+// are costing the planner the most actual rows.
+var sampleWorkload = []string{
+	"SELECT * FROM fire_calls WHERE call_type = 'Medical Incident'",
+	"SELECT * FROM fire_calls WHERE call_date = '01/01/2020'",
+	"SELECT * FROM fire_calls WHERE neighborhood = 'Tenderloin'",
+	"SELECT * FROM fire_calls WHERE unit_type = 'ENGINE'",
+	"SELECT COUNT(*) FROM fire_calls WHERE battalion = 'B01'",
+}
+
 func main() {
+	force := flag.Bool("force", false, "apply migrations even if applied checksums have drifted from disk")
+	flag.Parse()
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found or error loading it")
@@ -26,18 +43,26 @@ func main() {
 	// Create profiler to track performance
 	prof := profiler.New()
 
-	// Step 1: Create schema
+	// Step 1: Apply schema migrations
 	schemaOp := prof.Start("schema_creation")
 	schemaManager := schema.NewManager(db.GetPool())
-	if err := schemaManager.CreateFromFile("schema.sql"); err != nil {
-		log.Fatalf("❌ Failed to create schema: %v", err)
+	migrator, err := schema.NewMigrator(db.GetPool())
+	if err != nil {
+		log.Fatalf("❌ Failed to load migrations: %v", err)
+	}
+	migrator.Force = *force
+	migrationOp := schemaOp.Start("apply_migrations")
+	if err := migrator.Up(context.Background(), 0); err != nil {
+		log.Fatalf("❌ Failed to apply migrations: %v", err)
 	}
+	migrationOp.End()
 	schemaDuration := schemaOp.End()
-	fmt.Printf("✅ Schema created in: %v\n", schemaDuration)
+	fmt.Printf("✅ Schema migrated in: %v\n", schemaDuration)
 
 	// Step 2: Import CSV data
 	importOp := prof.Start("data_import")
 	csvImporter := importer.NewCSVImporter(db.GetPool(), 1000) // 1000 records per batch
+	csvImporter.BatchProfiler = importOp
 
 	// Choose import method:
 	// Option 1: Sequential import (simpler, easier to debug)
@@ -49,12 +74,23 @@ func main() {
 	if err != nil {
 		log.Fatalf("❌ Failed to import data: %v", err)
 	}
+	importOp.SetAttribute("records_imported", recordsImported)
 	importOp.End()
 
 	// Step 3: Print profiling report
 	prof.PrintReport(recordsImported)
 
-	// Step 4: Get and display table statistics
+	// Step 4: Analyze a representative workload and suggest index changes
+	advisorOp := prof.Start("index_advisory")
+	report, err := advisor.Analyze(sampleWorkload)
+	if err != nil {
+		log.Printf("⚠️  Warning: Index advisor failed: %v", err)
+	} else {
+		report.Print()
+	}
+	advisorOp.End()
+
+	// Step 5: Get and display table statistics
 	stats, err := schemaManager.GetTableStats()
 	if err != nil {
 		log.Printf("⚠️  Warning: Could not retrieve table stats: %v", err)
@@ -0,0 +1,313 @@
+package profiler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"database-optimizer/schema"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WorkloadItem is a single statement from a workload file, along with how
+// many times it should be executed relative to the other statements in the
+// same file. A statement's weight comes from an optional preceding
+// "-- @weight N" directive and defaults to 1.
+type WorkloadItem struct {
+	SQL    string
+	Weight int
+}
+
+// ParseWorkloadFile reads a workload file with one SQL statement per line.
+// A line of the form "-- @weight N" applies weight N to the statement that
+// follows it. Blank lines and other comment lines are ignored.
+func ParseWorkloadFile(path string) ([]WorkloadItem, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workload file: %w", err)
+	}
+	defer file.Close()
+
+	var items []WorkloadItem
+	pendingWeight := 1
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if weight, ok := parseWeightDirective(line); ok {
+			pendingWeight = weight
+			continue
+		}
+
+		if strings.HasPrefix(line, "--") {
+			continue
+		}
+
+		items = append(items, WorkloadItem{SQL: line, Weight: pendingWeight})
+		pendingWeight = 1
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read workload file: %w", err)
+	}
+
+	return items, nil
+}
+
+func parseWeightDirective(line string) (int, bool) {
+	const prefix = "-- @weight"
+	if !strings.HasPrefix(line, prefix) {
+		return 0, false
+	}
+
+	weight, err := strconv.Atoi(strings.TrimSpace(line[len(prefix):]))
+	if err != nil || weight < 1 {
+		return 0, false
+	}
+	return weight, true
+}
+
+// QueryLatencies holds the latency percentiles recorded for a single
+// statement during a scenario run.
+type QueryLatencies struct {
+	Count int64
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// StatStatementDelta is the change in pg_stat_statements counters for a
+// query observed between the start and end of a scenario run.
+type StatStatementDelta struct {
+	Query              string
+	CallsDelta         int64
+	TotalExecTimeDelta time.Duration
+}
+
+// ScenarioResult captures everything observed while running one scenario's
+// workload: per-query latency distribution, total wall time, and
+// server-side execution stats pulled from pg_stat_statements.
+type ScenarioResult struct {
+	Name           string
+	TotalWallTime  time.Duration
+	QueryLatencies map[string]QueryLatencies
+	StatStatements []StatStatementDelta
+}
+
+// Harness runs a workload against a configurable schema setup and records
+// latency and server-side stats for comparison across scenarios.
+type Harness struct {
+	pool *pgxpool.Pool
+}
+
+// NewHarness creates a workload replay harness against pool.
+func NewHarness(pool *pgxpool.Pool) *Harness {
+	return &Harness{pool: pool}
+}
+
+// RunScenario applies setup (e.g. schemaManager.CreateIndexes or DropIndexes)
+// to prepare the schema, then executes workload iterations times, recording
+// per-query latency percentiles and the pg_stat_statements delta observed
+// over the run.
+func (h *Harness) RunScenario(name string, setup func(*schema.Manager) error, workload []WorkloadItem, iterations int) (*ScenarioResult, error) {
+	ctx := context.Background()
+	mgr := schema.NewManager(h.pool)
+
+	if setup != nil {
+		if err := setup(mgr); err != nil {
+			return nil, fmt.Errorf("scenario %q: setup failed: %w", name, err)
+		}
+	}
+
+	before, err := snapshotStatStatements(ctx, h.pool)
+	if err != nil {
+		fmt.Printf("⚠️  pg_stat_statements unavailable, server-side stats will be empty: %v\n", err)
+		before = map[string]pgStatEntry{}
+	}
+
+	histograms := make(map[string]*histogram)
+
+	start := time.Now()
+	for i := 0; i < iterations; i++ {
+		for _, item := range workload {
+			for w := 0; w < item.Weight; w++ {
+				queryStart := time.Now()
+				if err := runQuery(ctx, h.pool, item.SQL); err != nil {
+					return nil, fmt.Errorf("scenario %q: query %q failed: %w", name, item.SQL, err)
+				}
+
+				hist, ok := histograms[item.SQL]
+				if !ok {
+					hist = newHistogram()
+					histograms[item.SQL] = hist
+				}
+				hist.record(time.Since(queryStart))
+			}
+		}
+	}
+	totalWallTime := time.Since(start)
+
+	after, err := snapshotStatStatements(ctx, h.pool)
+	if err != nil {
+		after = map[string]pgStatEntry{}
+	}
+
+	latencies := make(map[string]QueryLatencies, len(histograms))
+	for query, hist := range histograms {
+		latencies[query] = QueryLatencies{
+			Count: hist.total(),
+			P50:   hist.percentile(0.50),
+			P95:   hist.percentile(0.95),
+			P99:   hist.percentile(0.99),
+		}
+	}
+
+	return &ScenarioResult{
+		Name:           name,
+		TotalWallTime:  totalWallTime,
+		QueryLatencies: latencies,
+		StatStatements: diffStatStatements(before, after),
+	}, nil
+}
+
+// runQuery executes sql and fully drains its result set so that the
+// recorded latency includes data transfer, not just planning/execution.
+func runQuery(ctx context.Context, pool *pgxpool.Pool, sql string) error {
+	rows, err := pool.Query(ctx, sql)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	return rows.Err()
+}
+
+type pgStatEntry struct {
+	calls         int64
+	totalExecTime float64 // milliseconds
+}
+
+// snapshotStatStatements reads current call counts and total execution time
+// for fire_calls-related statements from pg_stat_statements. Callers should
+// treat a non-nil error as "extension not installed" and fall back to an
+// empty snapshot rather than failing the whole scenario.
+func snapshotStatStatements(ctx context.Context, pool *pgxpool.Pool) (map[string]pgStatEntry, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT query, calls, total_exec_time
+		FROM pg_stat_statements
+		WHERE query ILIKE '%fire_calls%'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_statements: %w", err)
+	}
+	defer rows.Close()
+
+	snapshot := make(map[string]pgStatEntry)
+	for rows.Next() {
+		var query string
+		var entry pgStatEntry
+		if err := rows.Scan(&query, &entry.calls, &entry.totalExecTime); err != nil {
+			return nil, fmt.Errorf("failed to scan pg_stat_statements row: %w", err)
+		}
+		snapshot[query] = entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pg_stat_statements rows: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func diffStatStatements(before, after map[string]pgStatEntry) []StatStatementDelta {
+	var deltas []StatStatementDelta
+	for query, a := range after {
+		b := before[query]
+		callsDelta := a.calls - b.calls
+		if callsDelta <= 0 {
+			continue
+		}
+		deltas = append(deltas, StatStatementDelta{
+			Query:              query,
+			CallsDelta:         callsDelta,
+			TotalExecTimeDelta: time.Duration((a.totalExecTime - b.totalExecTime) * float64(time.Millisecond)),
+		})
+	}
+	return deltas
+}
+
+// histogram is a fixed-memory, log2-bucketed latency counter in the spirit
+// of HdrHistogram: it trades exact percentiles for O(1) recording and a
+// bounded memory footprint regardless of how many samples are recorded.
+type histogram struct {
+	counts [numBuckets]int64
+}
+
+const numBuckets = 64
+const minBucketNs = 1000 // 1 microsecond floor, to keep low bucket indices meaningful
+
+func newHistogram() *histogram {
+	return &histogram{}
+}
+
+func (h *histogram) record(d time.Duration) {
+	h.counts[bucketFor(d)]++
+}
+
+func (h *histogram) total() int64 {
+	var total int64
+	for _, c := range h.counts {
+		total += c
+	}
+	return total
+}
+
+// percentile returns the upper bound of the bucket containing the p-th
+// percentile sample (p in [0, 1]).
+func (h *histogram) percentile(p float64) time.Duration {
+	total := h.total()
+	if total == 0 {
+		return 0
+	}
+
+	target := int64(math.Ceil(float64(total) * p))
+	var cumulative int64
+	for b, c := range h.counts {
+		cumulative += c
+		if cumulative >= target {
+			return bucketUpperBound(b)
+		}
+	}
+
+	return bucketUpperBound(numBuckets - 1)
+}
+
+func bucketFor(d time.Duration) int {
+	ns := d.Nanoseconds()
+	if ns < minBucketNs {
+		ns = minBucketNs
+	}
+	b := int(math.Log2(float64(ns)))
+	if b < 0 {
+		b = 0
+	}
+	if b >= numBuckets {
+		b = numBuckets - 1
+	}
+	return b
+}
+
+func bucketUpperBound(b int) time.Duration {
+	return time.Duration(math.Pow(2, float64(b+1)))
+}
@@ -2,89 +2,129 @@ package profiler
 
 import (
 	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
 	"time"
 )
 
-// Profiler tracks performance metrics for database operations
+// Event is a single timed span recorded by the Profiler. Events can nest:
+// an Operation.Start call made on another Operation attaches the new Event
+// as a child of the parent's, so e.g. schema_creation can contain
+// apply_migrations, which can contain individual migration steps.
+type Event struct {
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+	Parent     *Event
+	Children   []*Event
+}
+
+// Duration returns how long the event ran. It is zero until End has been
+// called on the Operation that owns this event.
+func (e *Event) Duration() time.Duration {
+	if e.End.IsZero() {
+		return 0
+	}
+	return e.End.Sub(e.Start)
+}
+
+// Profiler records a tree of Events describing the operations performed
+// during a run, and can hand that tree to a Reporter for output.
 type Profiler struct {
-	operations map[string]time.Duration
-	startTimes map[string]time.Time
+	mu    sync.Mutex
+	roots []*Event
 }
 
-// Operation represents a timed operation
+// Operation represents a single in-flight timed span. Call Start on it to
+// begin a nested child span, SetAttribute to attach debugging context, and
+// End to close it out.
 type Operation struct {
-	name     string
+	event    *Event
 	profiler *Profiler
 }
 
-// New creates a new profiler
+// New creates a new profiler.
 func New() *Profiler {
-	return &Profiler{
-		operations: make(map[string]time.Duration),
-		startTimes: make(map[string]time.Time),
-	}
+	return &Profiler{}
 }
 
-// Start begins timing an operation
-func (p *Profiler) Start(operationName string) *Operation {
-	p.startTimes[operationName] = time.Now()
-	return &Operation{
-		name:     operationName,
-		profiler: p,
-	}
+// Start begins timing a new top-level operation.
+func (p *Profiler) Start(name string) *Operation {
+	event := &Event{Name: name, Start: time.Now(), Attributes: make(map[string]interface{})}
+
+	p.mu.Lock()
+	p.roots = append(p.roots, event)
+	p.mu.Unlock()
+
+	return &Operation{event: event, profiler: p}
 }
 
-// End completes the timing of an operation and returns the duration
-func (op *Operation) End() time.Duration {
-	startTime, exists := op.profiler.startTimes[op.name]
-	if !exists {
-		return 0
-	}
+// Start begins timing a child operation nested under op.
+func (op *Operation) Start(name string) *Operation {
+	child := &Event{Name: name, Start: time.Now(), Attributes: make(map[string]interface{}), Parent: op.event}
 
-	duration := time.Since(startTime)
-	op.profiler.operations[op.name] = duration
-	delete(op.profiler.startTimes, op.name)
+	op.profiler.mu.Lock()
+	op.event.Children = append(op.event.Children, child)
+	op.profiler.mu.Unlock()
 
-	return duration
+	return &Operation{event: child, profiler: op.profiler}
 }
 
-// GetDuration returns the duration of a completed operation
-func (p *Profiler) GetDuration(operationName string) time.Duration {
-	return p.operations[operationName]
+// SetAttribute attaches debugging context to the operation's event, such as
+// a batch size or worker ID, for reporters that can make use of it.
+func (op *Operation) SetAttribute(key string, value interface{}) {
+	op.event.Attributes[key] = value
 }
 
-// PrintReport prints a formatted performance report
-func (p *Profiler) PrintReport(totalRecords int) {
-	fmt.Println("\n" + strings("=", 60))
-	fmt.Println("📊 PERFORMANCE REPORT")
-	fmt.Println(strings("=", 60))
-
-	// Calculate total time
-	var totalTime time.Duration
-	for _, duration := range p.operations {
-		totalTime += duration
-	}
+// End completes the timing of an operation and returns its duration.
+func (op *Operation) End() time.Duration {
+	op.event.End = time.Now()
+	return op.event.Duration()
+}
 
-	// Print individual operations
-	for name, duration := range p.operations {
-		fmt.Printf("⏱️  %-20s: %v\n", name, duration)
-	}
+// Events returns the top-level (root) events recorded so far. Each event's
+// Children field holds its nested spans.
+func (p *Profiler) Events() []*Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	fmt.Println(strings("-", 60))
+	events := make([]*Event, len(p.roots))
+	copy(events, p.roots)
+	return events
+}
+
+// Report hands the profiler's recorded events to r for output.
+func (p *Profiler) Report(r Reporter) error {
+	return r.Report(p.Events())
+}
 
-	// Calculate and display throughput
+// PrintReport prints a formatted performance report to stdout using
+// TextReporter. totalRecords, if positive, is attached to the data_import
+// event so the report can include a throughput line.
+func (p *Profiler) PrintReport(totalRecords int) {
 	if totalRecords > 0 {
-		importDuration := p.operations["data_import"]
-		if importDuration > 0 {
-			recordsPerSecond := float64(totalRecords) / importDuration.Seconds()
-			fmt.Printf("📈 Records imported    : %d\n", totalRecords)
-			fmt.Printf("⚡ Records per second  : %.2f\n", recordsPerSecond)
-			fmt.Println(strings("-", 60))
+		p.mu.Lock()
+		for _, e := range p.roots {
+			if e.Name == "data_import" {
+				if _, exists := e.Attributes["records_imported"]; !exists {
+					e.Attributes["records_imported"] = totalRecords
+				}
+			}
 		}
+		p.mu.Unlock()
 	}
 
-	fmt.Printf("🏁 Total execution time: %v\n", totalTime)
-	fmt.Println(strings("=", 60) + "\n")
+	_ = NewTextReporter(os.Stdout).Report(p.Events())
+}
+
+// Reset clears all recorded events.
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	p.roots = nil
+	p.mu.Unlock()
 }
 
 // PrintTableStats prints statistics about the database table
@@ -92,43 +132,19 @@ func PrintTableStats(totalRecords int, topCallTypes []struct {
 	CallType string
 	Count    int
 }) {
-	fmt.Println("\n" + strings("=", 60))
+	fmt.Println("\n" + repeat("=", 60))
 	fmt.Println("📋 TABLE STATISTICS")
-	fmt.Println(strings("=", 60))
+	fmt.Println(repeat("=", 60))
 	fmt.Printf("📊 Total records: %d\n", totalRecords)
 	fmt.Println("\n🔥 Top 5 Call Types:")
-	fmt.Println(strings("-", 60))
+	fmt.Println(repeat("-", 60))
 
 	for i, ct := range topCallTypes {
 		percentage := float64(ct.Count) / float64(totalRecords) * 100
 		fmt.Printf("%d. %-30s: %6d (%.2f%%)\n", i+1, ct.CallType, ct.Count, percentage)
 	}
 
-	fmt.Println(strings("=", 60) + "\n")
-}
-
-// strings creates a string by repeating a character n times
-func strings(char string, count int) string {
-	result := ""
-	for i := 0; i < count; i++ {
-		result += char
-	}
-	return result
-}
-
-// Reset clears all profiling data
-func (p *Profiler) Reset() {
-	p.operations = make(map[string]time.Duration)
-	p.startTimes = make(map[string]time.Time)
-}
-
-// GetAllOperations returns a copy of all recorded operations
-func (p *Profiler) GetAllOperations() map[string]time.Duration {
-	operations := make(map[string]time.Duration)
-	for k, v := range p.operations {
-		operations[k] = v
-	}
-	return operations
+	fmt.Println(repeat("=", 60) + "\n")
 }
 
 // FormatDuration formats a duration into a human-readable string
@@ -148,26 +164,62 @@ func FormatDuration(d time.Duration) string {
 	}
 }
 
-// CompareResults compares two profiling results and prints the difference
-func CompareResults(name1 string, duration1 time.Duration, name2 string, duration2 time.Duration) {
-	fmt.Println("\n" + strings("=", 60))
+// CompareResults compares two harness scenario results, printing the
+// per-query p50 speedup for every statement they have in common plus an
+// aggregate geometric-mean improvement, so index changes can be quantified
+// against a realistic workload instead of eyeballed.
+func CompareResults(a, b *ScenarioResult) {
+	fmt.Println("\n" + repeat("=", 60))
 	fmt.Println("⚖️  PERFORMANCE COMPARISON")
-	fmt.Println(strings("=", 60))
-	fmt.Printf("%-30s: %v\n", name1, duration1)
-	fmt.Printf("%-30s: %v\n", name2, duration2)
-	fmt.Println(strings("-", 60))
-
-	if duration1 > duration2 {
-		improvement := float64(duration1-duration2) / float64(duration1) * 100
-		speedup := float64(duration1) / float64(duration2)
-		fmt.Printf("✅ %s is %.2f%% faster (%.2fx speedup)\n", name2, improvement, speedup)
-	} else if duration2 > duration1 {
-		improvement := float64(duration2-duration1) / float64(duration2) * 100
-		speedup := float64(duration2) / float64(duration1)
-		fmt.Printf("✅ %s is %.2f%% faster (%.2fx speedup)\n", name1, improvement, speedup)
+	fmt.Println(repeat("=", 60))
+	fmt.Printf("%-30s: %v\n", a.Name, a.TotalWallTime)
+	fmt.Printf("%-30s: %v\n", b.Name, b.TotalWallTime)
+	fmt.Println(repeat("-", 60))
+
+	queries := make([]string, 0, len(a.QueryLatencies))
+	for query := range a.QueryLatencies {
+		if _, ok := b.QueryLatencies[query]; ok {
+			queries = append(queries, query)
+		}
+	}
+	sort.Strings(queries)
+
+	var logSpeedupSum float64
+	var speedupCount int
+
+	for _, query := range queries {
+		aLatency := a.QueryLatencies[query].P50
+		bLatency := b.QueryLatencies[query].P50
+		if aLatency <= 0 || bLatency <= 0 {
+			continue
+		}
+
+		speedup := float64(aLatency) / float64(bLatency)
+		logSpeedupSum += math.Log(speedup)
+		speedupCount++
+
+		fmt.Printf("%-60s %s: %-10v %s: %-10v %.2fx\n", truncateQuery(query), a.Name, aLatency, b.Name, bLatency, speedup)
+	}
+
+	fmt.Println(repeat("-", 60))
+	if speedupCount == 0 {
+		fmt.Println("⚖️  No comparable queries between scenarios")
 	} else {
-		fmt.Println("⚖️  Both methods took the same time")
+		geoMean := math.Exp(logSpeedupSum / float64(speedupCount))
+		if geoMean >= 1 {
+			fmt.Printf("✅ %s is %.2fx faster on average (geometric mean, %d queries)\n", b.Name, geoMean, speedupCount)
+		} else {
+			fmt.Printf("✅ %s is %.2fx faster on average (geometric mean, %d queries)\n", a.Name, 1/geoMean, speedupCount)
+		}
 	}
 
-	fmt.Println(strings("=", 60) + "\n")
+	fmt.Println(repeat("=", 60) + "\n")
+}
+
+func truncateQuery(query string) string {
+	const maxLen = 57
+	if len(query) <= maxLen {
+		return query
+	}
+	return query[:maxLen] + "..."
 }
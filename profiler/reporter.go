@@ -0,0 +1,226 @@
+package profiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Reporter turns a Profiler's recorded event tree into output, so the same
+// events can drive a human-readable report, a machine-readable log, or a
+// metrics endpoint without the Profiler knowing about any of them.
+type Reporter interface {
+	Report(events []*Event) error
+}
+
+// TextReporter writes the same human-readable report PrintReport has always
+// produced, now able to walk nested child spans with indentation.
+type TextReporter struct {
+	Writer io.Writer
+}
+
+// NewTextReporter creates a TextReporter writing to w.
+func NewTextReporter(w io.Writer) *TextReporter {
+	return &TextReporter{Writer: w}
+}
+
+// Report writes events as an indented, emoji-annotated performance report.
+func (r *TextReporter) Report(events []*Event) error {
+	w := r.Writer
+
+	fmt.Fprintln(w, "\n"+repeat("=", 60))
+	fmt.Fprintln(w, "📊 PERFORMANCE REPORT")
+	fmt.Fprintln(w, repeat("=", 60))
+
+	var totalTime time.Duration
+	for _, e := range events {
+		totalTime += e.Duration()
+		writeEventText(w, e, 0)
+	}
+
+	fmt.Fprintln(w, repeat("-", 60))
+
+	for _, e := range events {
+		if e.Name != "data_import" {
+			continue
+		}
+		n, ok := e.Attributes["records_imported"].(int)
+		if !ok || n <= 0 || e.Duration() <= 0 {
+			continue
+		}
+		recordsPerSecond := float64(n) / e.Duration().Seconds()
+		fmt.Fprintf(w, "📈 Records imported    : %d\n", n)
+		fmt.Fprintf(w, "⚡ Records per second  : %.2f\n", recordsPerSecond)
+		fmt.Fprintln(w, repeat("-", 60))
+	}
+
+	fmt.Fprintf(w, "🏁 Total execution time: %v\n", totalTime)
+	fmt.Fprintln(w, repeat("=", 60)+"\n")
+
+	return nil
+}
+
+func writeEventText(w io.Writer, e *Event, depth int) {
+	fmt.Fprintf(w, "%s⏱️  %-20s: %v\n", repeat("  ", depth), e.Name, e.Duration())
+	for _, child := range e.Children {
+		writeEventText(w, child, depth+1)
+	}
+}
+
+// JSONReporter writes one JSON object per event (including nested children,
+// flattened with a parent reference) to Writer, suitable for ingestion by a
+// log pipeline or CI artifact.
+type JSONReporter struct {
+	Writer io.Writer
+}
+
+// NewJSONReporter creates a JSONReporter writing line-delimited JSON to w.
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{Writer: w}
+}
+
+type jsonEvent struct {
+	Name       string                 `json:"name"`
+	Parent     string                 `json:"parent,omitempty"`
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"`
+	DurationMs float64                `json:"duration_ms"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Report writes events as line-delimited JSON.
+func (r *JSONReporter) Report(events []*Event) error {
+	enc := json.NewEncoder(r.Writer)
+
+	var emit func(e *Event) error
+	emit = func(e *Event) error {
+		parent := ""
+		if e.Parent != nil {
+			parent = e.Parent.Name
+		}
+
+		if err := enc.Encode(jsonEvent{
+			Name:       e.Name,
+			Parent:     parent,
+			Start:      e.Start,
+			End:        e.End,
+			DurationMs: float64(e.Duration().Microseconds()) / 1000,
+			Attributes: e.Attributes,
+		}); err != nil {
+			return fmt.Errorf("failed to encode event %q: %w", e.Name, err)
+		}
+
+		for _, child := range e.Children {
+			if err := emit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, e := range events {
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PromReporter renders the profiler's events as Prometheus text exposition
+// format. It implements http.Handler directly, so it can be mounted on
+// whatever mux the caller already runs, e.g. mux.Handle("/metrics", reporter).
+type PromReporter struct {
+	events []*Event
+}
+
+// NewPromReporter creates an empty PromReporter; call Report to populate it
+// before mounting it as an http.Handler.
+func NewPromReporter() *PromReporter {
+	return &PromReporter{}
+}
+
+// Report stores events for the next ServeHTTP call to render.
+func (r *PromReporter) Report(events []*Event) error {
+	r.events = events
+	return nil
+}
+
+// ServeHTTP renders import_records_total, import_batch_duration_seconds and
+// schema_operation_duration_seconds in Prometheus text exposition format.
+func (r *PromReporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var recordsTotal int64
+	var batchDurations []float64
+	schemaDurations := make(map[string]time.Duration)
+
+	var walk func(e *Event)
+	walk = func(e *Event) {
+		switch {
+		case e.Name == "data_import":
+			if n, ok := e.Attributes["records_imported"].(int); ok {
+				recordsTotal += int64(n)
+			}
+		case strings.HasPrefix(e.Name, "batch_worker_"):
+			batchDurations = append(batchDurations, e.Duration().Seconds())
+		case e.Name == "schema_creation" || strings.HasPrefix(e.Name, "create_"):
+			schemaDurations[e.Name] = e.Duration()
+		}
+		for _, child := range e.Children {
+			walk(child)
+		}
+	}
+	for _, e := range r.events {
+		walk(e)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintln(w, "# HELP import_records_total Total records imported")
+	fmt.Fprintln(w, "# TYPE import_records_total counter")
+	fmt.Fprintf(w, "import_records_total %d\n", recordsTotal)
+
+	fmt.Fprintln(w, "# HELP import_batch_duration_seconds Duration of each import batch")
+	fmt.Fprintln(w, "# TYPE import_batch_duration_seconds histogram")
+	writePromHistogram(w, "import_batch_duration_seconds", batchDurations)
+
+	fmt.Fprintln(w, "# HELP schema_operation_duration_seconds Duration of schema operations")
+	fmt.Fprintln(w, "# TYPE schema_operation_duration_seconds gauge")
+	for name, d := range schemaDurations {
+		fmt.Fprintf(w, "schema_operation_duration_seconds{operation=%q} %f\n", name, d.Seconds())
+	}
+}
+
+var promHistogramBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+func writePromHistogram(w io.Writer, name string, samples []float64) {
+	counts := make([]int, len(promHistogramBuckets))
+	var sum float64
+
+	for _, s := range samples {
+		sum += s
+		for i, bound := range promHistogramBuckets {
+			if s <= bound {
+				counts[i]++
+			}
+		}
+	}
+
+	for i, bound := range promHistogramBuckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, bound, counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, len(samples))
+	fmt.Fprintf(w, "%s_sum %f\n", name, sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, len(samples))
+}
+
+// repeat builds a string by repeating s count times.
+func repeat(s string, count int) string {
+	result := ""
+	for i := 0; i < count; i++ {
+		result += s
+	}
+	return result
+}
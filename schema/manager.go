@@ -3,7 +3,6 @@ package schema
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -30,25 +29,6 @@ func NewManager(pool *pgxpool.Pool) *Manager {
 	return &Manager{pool: pool}
 }
 
-// CreateFromFile reads and executes a SQL schema file
-func (m *Manager) CreateFromFile(filepath string) error {
-	ctx := context.Background()
-
-	// Read the SQL file
-	sqlBytes, err := os.ReadFile(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to read schema file: %w", err)
-	}
-
-	// Execute the SQL commands
-	_, err = m.pool.Exec(ctx, string(sqlBytes))
-	if err != nil {
-		return fmt.Errorf("failed to execute schema: %w", err)
-	}
-
-	return nil
-}
-
 // GetTableStats retrieves statistics about the fire_calls table
 func (m *Manager) GetTableStats() (*TableStats, error) {
 	ctx := context.Background()
@@ -89,17 +69,10 @@ func (m *Manager) GetTableStats() (*TableStats, error) {
 	return stats, nil
 }
 
-// DropTable drops the fire_calls table if it exists
-func (m *Manager) DropTable() error {
-	ctx := context.Background()
-	_, err := m.pool.Exec(ctx, "DROP TABLE IF EXISTS fire_calls")
-	if err != nil {
-		return fmt.Errorf("failed to drop table: %w", err)
-	}
-	return nil
-}
-
-// CreateIndexes creates all recommended indexes for the fire_calls table
+// CreateIndexes creates all recommended indexes for the fire_calls table.
+// This mirrors migration 0002 in schema/migrations; it exists as a direct
+// method as well so callers like profiler.Harness can toggle indexes on and
+// off between benchmark scenarios without going through the migrator.
 func (m *Manager) CreateIndexes() error {
 	ctx := context.Background()
 
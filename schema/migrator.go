@@ -0,0 +1,358 @@
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the advisory lock key Migrator holds for the duration
+// of an Up/Down/Redo run, so two concurrent invocations of main don't race
+// applying the same migration twice.
+const migrationLockKey = 8271001
+
+var migrationFilenameRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migration is one versioned schema change, loaded from a pair of
+// NNNN_description.up.sql / NNNN_description.down.sql files.
+type migration struct {
+	version     int64
+	description string
+	upSQL       []byte
+	downSQL     []byte
+	checksum    string
+}
+
+// MigrationState describes whether a known migration has been applied.
+type MigrationState struct {
+	Version     int64
+	Description string
+	Applied     bool
+	AppliedAt   time.Time
+}
+
+// Migrator applies and rolls back versioned schema migrations tracked in the
+// schema_migrations table. Set Force to true to apply migrations even when a
+// checksum mismatch is detected against what's already recorded.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []migration
+	Force      bool
+}
+
+// NewMigrator loads and validates the embedded migration set and returns a
+// Migrator ready to run against pool.
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int64]*migration)
+	for _, entry := range entries {
+		match := migrationFilenameRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, description: match[2]}
+			byVersion[version] = m
+		}
+
+		switch match[3] {
+		case "up":
+			m.upSQL = content
+			sum := sha256.Sum256(content)
+			m.checksum = hex.EncodeToString(sum[:])
+		case "down":
+			m.downSQL = content
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.upSQL == nil {
+			return nil, fmt.Errorf("migration %04d_%s is missing its .up.sql file", m.version, m.description)
+		}
+		if m.downSQL == nil {
+			return nil, fmt.Errorf("migration %04d_%s is missing its .down.sql file", m.version, m.description)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	return migrations, nil
+}
+
+const createTrackingTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    checksum   TEXT NOT NULL
+)`
+
+type appliedMigration struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// withLock runs fn while holding a session-level Postgres advisory lock,
+// ensuring the tracking table exists and verifying recorded checksums
+// against the migration files on disk before fn executes.
+func (mig *Migrator) withLock(ctx context.Context, fn func(ctx context.Context, conn *pgxpool.Conn, applied map[int64]appliedMigration) error) error {
+	conn, err := mig.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration advisory lock: %w", err)
+	}
+	defer conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	if _, err := conn.Exec(ctx, createTrackingTableSQL); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := mig.loadApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if !mig.Force {
+		if err := mig.verifyChecksums(applied); err != nil {
+			return err
+		}
+	}
+
+	return fn(ctx, conn, applied)
+}
+
+func (mig *Migrator) loadApplied(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.Query(ctx, "SELECT version, applied_at, checksum FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var version int64
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.appliedAt, &a.checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations row: %w", err)
+		}
+		applied[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schema_migrations rows: %w", err)
+	}
+
+	return applied, nil
+}
+
+// verifyChecksums refuses to proceed if a previously applied migration's
+// file has changed on disk since it was run, unless Force is set.
+func (mig *Migrator) verifyChecksums(applied map[int64]appliedMigration) error {
+	for _, m := range mig.migrations {
+		a, ok := applied[m.version]
+		if !ok {
+			continue
+		}
+		if a.checksum != m.checksum {
+			return fmt.Errorf("checksum drift detected for migration %04d_%s: applied checksum %s does not match file checksum %s (pass --force to proceed anyway)", m.version, m.description, a.checksum, m.checksum)
+		}
+	}
+	return nil
+}
+
+// Up applies up to n pending migrations in version order. n <= 0 means apply
+// all pending migrations.
+func (mig *Migrator) Up(ctx context.Context, n int) error {
+	return mig.withLock(ctx, func(ctx context.Context, conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		return mig.runUp(ctx, conn, applied, n)
+	})
+}
+
+// runUp applies up to n pending migrations in version order, assuming the
+// caller already holds the migration advisory lock. applied is updated in
+// place as each migration is applied, so callers that need to chain runUp
+// with runDown (e.g. Redo) see an up-to-date view without re-querying.
+func (mig *Migrator) runUp(ctx context.Context, conn *pgxpool.Conn, applied map[int64]appliedMigration, n int) error {
+	applyCount := 0
+	for _, m := range mig.migrations {
+		if n > 0 && applyCount >= n {
+			break
+		}
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+
+		if err := mig.applyUp(ctx, conn, m); err != nil {
+			return err
+		}
+		applied[m.version] = appliedMigration{checksum: m.checksum, appliedAt: time.Now()}
+		applyCount++
+	}
+	return nil
+}
+
+func (mig *Migrator) applyUp(ctx context.Context, conn *pgxpool.Conn, m migration) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for migration %04d: %w", m.version, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, string(m.upSQL)); err != nil {
+		return fmt.Errorf("failed to apply migration %04d_%s: %w", m.version, m.description, err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO schema_migrations (version, applied_at, checksum) VALUES ($1, now(), $2)",
+		m.version, m.checksum,
+	); err != nil {
+		return fmt.Errorf("failed to record migration %04d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %04d: %w", m.version, err)
+	}
+
+	return nil
+}
+
+// Down rolls back up to n of the most recently applied migrations, in
+// reverse version order. n <= 0 means roll back everything.
+func (mig *Migrator) Down(ctx context.Context, n int) error {
+	return mig.withLock(ctx, func(ctx context.Context, conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		return mig.runDown(ctx, conn, applied, n)
+	})
+}
+
+// runDown rolls back up to n of the most recently applied migrations, in
+// reverse version order, assuming the caller already holds the migration
+// advisory lock. applied is updated in place as each migration is rolled
+// back, so callers that need to chain runDown with runUp (e.g. Redo) see an
+// up-to-date view without re-querying.
+func (mig *Migrator) runDown(ctx context.Context, conn *pgxpool.Conn, applied map[int64]appliedMigration, n int) error {
+	var toRollback []migration
+	for i := len(mig.migrations) - 1; i >= 0; i-- {
+		m := mig.migrations[i]
+		if _, ok := applied[m.version]; !ok {
+			continue
+		}
+		toRollback = append(toRollback, m)
+		if n > 0 && len(toRollback) >= n {
+			break
+		}
+	}
+
+	for _, m := range toRollback {
+		if err := mig.applyDown(ctx, conn, m); err != nil {
+			return err
+		}
+		delete(applied, m.version)
+	}
+	return nil
+}
+
+func (mig *Migrator) applyDown(ctx context.Context, conn *pgxpool.Conn, m migration) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for migration %04d: %w", m.version, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, string(m.downSQL)); err != nil {
+		return fmt.Errorf("failed to roll back migration %04d_%s: %w", m.version, m.description, err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.version); err != nil {
+		return fmt.Errorf("failed to remove migration record %04d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit rollback of migration %04d: %w", m.version, err)
+	}
+
+	return nil
+}
+
+// Redo rolls back the most recently applied migration and reapplies it,
+// holding the migration advisory lock for the whole operation so a
+// concurrent migrator run can't apply or roll back anything in between.
+func (mig *Migrator) Redo(ctx context.Context) error {
+	return mig.withLock(ctx, func(ctx context.Context, conn *pgxpool.Conn, applied map[int64]appliedMigration) error {
+		if err := mig.runDown(ctx, conn, applied, 1); err != nil {
+			return fmt.Errorf("redo: failed to roll back: %w", err)
+		}
+		if err := mig.runUp(ctx, conn, applied, 1); err != nil {
+			return fmt.Errorf("redo: failed to reapply: %w", err)
+		}
+		return nil
+	})
+}
+
+// Status reports every known migration and whether it has been applied.
+func (mig *Migrator) Status(ctx context.Context) ([]MigrationState, error) {
+	conn, err := mig.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, createTrackingTableSQL); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := mig.loadApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(mig.migrations))
+	for _, m := range mig.migrations {
+		state := MigrationState{Version: m.version, Description: m.description}
+		if a, ok := applied[m.version]; ok {
+			state.Applied = true
+			state.AppliedAt = a.appliedAt
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}